@@ -4,16 +4,21 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"image"
 	"image/png" // Import PNG encoder
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 
-	// Import local raw package
+	// Import local cache, metadata and raw packages
+	"pic-viewer/cache"
+	"pic-viewer/metadata"
 	"pic-viewer/raw"
 
 	// Keep other decoders if needed for other formats
@@ -22,11 +27,28 @@ import (
 
 	"github.com/facette/natsort"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"golang.org/x/image/draw"
 )
 
+// thumbMaxDim is the maxDim passed to Cache.Key for entries holding a full-resolution,
+// un-downsampled encode (i.e. what ReadImage itself serves).
+const thumbMaxDim = 0
+
+// ErrNoPreviewAvailable is returned by ReadThumbnail for formats Go's
+// image.Decode can't handle (BMP, WebP, HEIC): ReadImage still serves
+// these via its raw-bytes fallback, but there's no decoded image.Image
+// to downsample into a thumbnail from. Callers can match on this to
+// distinguish "no preview for this format" from a genuine decode error.
+var ErrNoPreviewAvailable = errors.New("no preview available for this format")
+
 // App struct
 type App struct {
-	ctx context.Context
+	ctx            context.Context
+	thumbCache     *cache.Cache
+	metadataLoader *metadata.Loader
+
+	preloadMu     sync.Mutex
+	preloadCancel context.CancelFunc
 }
 
 // NewApp creates a new App application struct
@@ -38,6 +60,34 @@ func NewApp() *App {
 // so we can call the runtime methods
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
+
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = os.TempDir()
+	}
+	thumbCache, err := cache.New(filepath.Join(cacheDir, "pic-viewer", "thumbnails"), cache.DefaultMaxBytes)
+	if err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("failed to initialize thumbnail cache: %v", err))
+		return
+	}
+	a.thumbCache = thumbCache
+
+	metadataLoader, err := metadata.NewLoader()
+	if err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("failed to initialize metadata loader: %v", err))
+		return
+	}
+	a.metadataLoader = metadataLoader
+}
+
+// shutdown is called when the app is closing, giving us a chance to stop
+// the exiftool subprocess backing the metadata loader.
+func (a *App) shutdown(ctx context.Context) {
+	if a.metadataLoader != nil {
+		if err := a.metadataLoader.Close(); err != nil {
+			runtime.LogWarningf(a.ctx, "failed to close metadata loader: %v", err)
+		}
+	}
 }
 
 // Greet returns a greeting for the given name
@@ -57,31 +107,43 @@ func (a *App) SelectFolder() (string, error) {
 	return selection, nil
 }
 
-// ListImages returns a list of image file paths in a directory
+// sniffFileFormat reads path's leading bytes and identifies its image
+// format by magic number rather than trusting its extension.
+func sniffFileFormat(path string) (raw.Format, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return raw.FormatUnknown, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	header := make([]byte, 16)
+	n, err := file.Read(header)
+	if err != nil && err != io.EOF {
+		return raw.FormatUnknown, fmt.Errorf("failed to read header of %s: %w", path, err)
+	}
+	return raw.SniffFormat(header[:n]), nil
+}
+
+// ListImages returns a list of image file paths in a directory, detected
+// by content rather than extension so mislabeled or extensionless files
+// are still picked up.
 func (a *App) ListImages(dirPath string) ([]string, error) {
 	var imageFiles []string
-	validExtensions := map[string]bool{
-		// Standard formats
-		".jpg":  true,
-		".jpeg": true,
-		".png":  true,
-		".gif":  true,
-		".bmp":  true,
-		".webp": true,
-		// Fuji RAW format handled by local package
-		".raf": true,
-	}
-
-	// ...existing WalkDir logic...
+
 	err := filepath.WalkDir(dirPath, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
-		if !d.IsDir() && filepath.Dir(path) == dirPath {
-			ext := strings.ToLower(filepath.Ext(path))
-			if validExtensions[ext] {
-				imageFiles = append(imageFiles, path)
-			}
+		if d.IsDir() || filepath.Dir(path) != dirPath {
+			return nil
+		}
+		format, sniffErr := sniffFileFormat(path)
+		if sniffErr != nil {
+			runtime.LogWarningf(a.ctx, "ListImages: skipping %s: %v", path, sniffErr)
+			return nil
+		}
+		if format != raw.FormatUnknown {
+			imageFiles = append(imageFiles, path)
 		}
 		return nil
 	})
@@ -96,10 +158,34 @@ func (a *App) ListImages(dirPath string) ([]string, error) {
 
 // ReadImage reads an image file (including HDR and RAF) and returns its base64 encoded content
 func (a *App) ReadImage(filePath string) (encodedImage string, err error) {
-	ext := strings.ToLower(filepath.Ext(filePath))
+	format, sniffErr := sniffFileFormat(filePath)
+	if sniffErr != nil {
+		err = sniffErr
+		return
+	}
+
+	// Serve from the on-disk cache when possible, and populate it on a
+	// successful decode so repeat views of the same file (RAF and large
+	// HDR images especially) skip the decode/base64 work entirely.
+	var cacheKey string
+	if a.thumbCache != nil {
+		if key, keyErr := a.thumbCache.Key(filePath, thumbMaxDim); keyErr == nil {
+			cacheKey = key
+			if cached, ok := a.thumbCache.Get(cacheKey); ok {
+				return string(cached), nil
+			}
+		}
+	}
+	defer func() {
+		if cacheKey != "" && err == nil && encodedImage != "" {
+			if putErr := a.thumbCache.Put(cacheKey, []byte(encodedImage)); putErr != nil {
+				runtime.LogWarningf(a.ctx, "failed to cache preview for %s: %v", filePath, putErr)
+			}
+		}
+	}()
 
 	// Handle RAF files using the local raw package
-	if ext == ".raf" {
+	if format == raw.FormatRAF {
 		// Use panic recovery in case raw.ReadRAF panics
 		defer func() {
 			if r := recover(); r != nil {
@@ -159,20 +245,22 @@ func (a *App) ReadImage(filePath string) (encodedImage string, err error) {
 			return // Return read error
 		}
 		var mimeType string
-		switch ext {
-		case ".jpg", ".jpeg":
+		switch format {
+		case raw.FormatJPEG:
 			mimeType = "image/jpeg"
-		case ".png":
+		case raw.FormatPNG:
 			mimeType = "image/png"
-		case ".gif":
+		case raw.FormatGIF:
 			mimeType = "image/gif"
-		case ".bmp":
+		case raw.FormatBMP:
 			mimeType = "image/bmp"
-		case ".webp":
+		case raw.FormatWebP:
 			mimeType = "image/webp"
+		case raw.FormatHEIC:
+			mimeType = "image/heic"
 		default:
 			// Log unsupported format during fallback
-			runtime.LogWarningf(a.ctx, "Unsupported format '%s' encountered during fallback for file %s", ext, filePath)
+			runtime.LogWarningf(a.ctx, "Unsupported format '%s' encountered during fallback for file %s", format, filePath)
 			mimeType = "application/octet-stream" // Or return an error?
 		}
 		encoded := base64.StdEncoding.EncodeToString(data)
@@ -183,6 +271,13 @@ func (a *App) ReadImage(filePath string) (encodedImage string, err error) {
 
 	runtime.LogInfof(a.ctx, "Decoded format: %s for file %s", formatName, filePath)
 
+	// Auto-rotate according to the EXIF orientation tag, if we can read one.
+	if a.metadataLoader != nil {
+		if meta, metaErr := a.metadataLoader.Get(filePath); metaErr == nil {
+			img = applyOrientation(img, meta.Orientation)
+		}
+	}
+
 	// Encode successfully decoded images (non-RAF, non-fallback) as PNG
 	var buf bytes.Buffer
 	encodeErr := png.Encode(&buf, img)
@@ -197,6 +292,183 @@ func (a *App) ReadImage(filePath string) (encodedImage string, err error) {
 	return    // Return success (encodedImage, nil)
 }
 
+// ImageFormat selects the output encoding for ReadImageWithOpts.
+type ImageFormat string
+
+const (
+	FormatPNG  ImageFormat = "png"
+	FormatWebP ImageFormat = "webp"
+	FormatAVIF ImageFormat = "avif"
+)
+
+// ReadImageOpts configures ReadImageWithOpts's resize and output format.
+// When both Width and Height are given the image is cropped to fill them.
+// When only one is given, the image is scaled, aspect ratio preserved, to
+// fit within a square of that size, so the other dimension is bounded by
+// it too. When both are 0 the image is not resized.
+type ReadImageOpts struct {
+	Width  int
+	Height int
+	Format ImageFormat
+}
+
+// ReadImageWithOpts is like ReadImage but resizes and re-encodes via the
+// libvips backend when the app is built with the "vips" tag, which is
+// dramatically smaller over the wire than base64-PNG and adds proper
+// BMP/WebP support. Builds without that tag fall back to ReadImage,
+// ignoring the resize/format request.
+func (a *App) ReadImageWithOpts(filePath string, opts ReadImageOpts) (string, error) {
+	if !vipsEnabled {
+		runtime.LogWarningf(a.ctx, "ReadImageWithOpts: vips backend unavailable, falling back to ReadImage for %s", filePath)
+		return a.ReadImage(filePath)
+	}
+	return a.readImageVips(filePath, opts)
+}
+
+// ReadThumbnail returns a base64 encoded PNG preview of filePath downsampled
+// so neither dimension exceeds maxDim, serving from the on-disk thumbnail
+// cache when available. This avoids decoding and re-encoding full
+// resolution RAF/HDR images on every browse step.
+func (a *App) ReadThumbnail(filePath string, maxDim int) (string, error) {
+	if maxDim <= 0 {
+		return "", fmt.Errorf("maxDim must be positive, got %d", maxDim)
+	}
+
+	var cacheKey string
+	if a.thumbCache != nil {
+		if key, keyErr := a.thumbCache.Key(filePath, maxDim); keyErr == nil {
+			cacheKey = key
+			if cached, ok := a.thumbCache.Get(cacheKey); ok {
+				return string(cached), nil
+			}
+		}
+	}
+
+	img, err := decodeForPreview(a.ctx, filePath)
+	if err != nil {
+		return "", err
+	}
+
+	thumb := resizeToMaxDim(img, maxDim)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, thumb); err != nil {
+		return "", fmt.Errorf("failed to encode thumbnail for %s: %w", filePath, err)
+	}
+	encodedImage := fmt.Sprintf("data:image/png;base64,%s", base64.StdEncoding.EncodeToString(buf.Bytes()))
+
+	if cacheKey != "" {
+		if putErr := a.thumbCache.Put(cacheKey, []byte(encodedImage)); putErr != nil {
+			runtime.LogWarningf(a.ctx, "failed to cache thumbnail for %s: %v", filePath, putErr)
+		}
+	}
+	return encodedImage, nil
+}
+
+// GetMetadata returns EXIF/XMP metadata (camera, lens, shutter, ISO, GPS,
+// orientation, capture time) for path.
+func (a *App) GetMetadata(path string) (metadata.Metadata, error) {
+	if a.metadataLoader == nil {
+		return metadata.Metadata{}, fmt.Errorf("metadata loader is not available")
+	}
+	return a.metadataLoader.Get(path)
+}
+
+// GetMetadataBatch returns metadata for each of paths, in order. The
+// underlying loader batches these into as few exiftool invocations as
+// possible rather than spawning one process per file.
+func (a *App) GetMetadataBatch(paths []string) ([]metadata.Metadata, error) {
+	if a.metadataLoader == nil {
+		return nil, fmt.Errorf("metadata loader is not available")
+	}
+	return a.metadataLoader.GetBatch(paths)
+}
+
+// ClearCache empties the on-disk thumbnail/preview cache.
+func (a *App) ClearCache() error {
+	if a.thumbCache == nil {
+		return nil
+	}
+	return a.thumbCache.Clear()
+}
+
+// decodeForPreview decodes filePath (including RAF via the raw package)
+// into an image.Image suitable for downsampling.
+func decodeForPreview(ctx context.Context, filePath string) (img image.Image, err error) {
+	format, err := sniffFileFormat(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == raw.FormatRAF {
+		// Use panic recovery in case raw.ReadRAF panics, same as ReadImage's RAF branch.
+		defer func() {
+			if r := recover(); r != nil {
+				errMsg := fmt.Sprintf("panic occurred while decoding RAF file %s: %v", filePath, r)
+				runtime.LogError(ctx, errMsg)
+				img = nil
+				err = fmt.Errorf(errMsg)
+			}
+		}()
+
+		rafData := raw.ReadRAF(filePath)
+		if rafData == nil || len(rafData.Jpeg) == 0 {
+			return nil, fmt.Errorf("failed to extract JPEG from RAF file %s", filePath)
+		}
+		img, _, err = image.Decode(bytes.NewReader(rafData.Jpeg))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode JPEG extracted from RAF file %s: %w", filePath, err)
+		}
+		return img, nil
+	}
+
+	// ReadImage falls back to serving these formats as raw bytes since
+	// Go's image.Decode can't handle them, but that leaves nothing to
+	// downsample here; report it distinctly rather than as a decode failure.
+	if format == raw.FormatBMP || format == raw.FormatWebP || format == raw.FormatHEIC {
+		return nil, fmt.Errorf("%w: %s", ErrNoPreviewAvailable, format)
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", filePath, err)
+	}
+	defer file.Close()
+
+	img, _, err = image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode file %s: %w", filePath, err)
+	}
+	return img, nil
+}
+
+// resizeToMaxDim downsamples img so neither dimension exceeds maxDim,
+// preserving aspect ratio. Images already within bounds are returned
+// unchanged.
+func resizeToMaxDim(img image.Image, maxDim int) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= maxDim && h <= maxDim {
+		return img
+	}
+
+	scale := float64(maxDim) / float64(w)
+	if h > w {
+		scale = float64(maxDim) / float64(h)
+	}
+	dstW := int(float64(w) * scale)
+	dstH := int(float64(h) * scale)
+	if dstW < 1 {
+		dstW = 1
+	}
+	if dstH < 1 {
+		dstH = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	draw.ApproxBiLinear.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+	return dst
+}
+
 // Folder represents a directory in the tree view
 type Folder struct {
 	Name     string    `json:"name"`
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ArrangeScheme selects how ArrangeFolder lays out destination folders.
+type ArrangeScheme string
+
+const (
+	// ArrangeByDate files under dst/YYYY/MM/DD.
+	ArrangeByDate ArrangeScheme = "date"
+	// ArrangeByDateCamera additionally splits each date folder by the
+	// camera model reported in EXIF, when available.
+	ArrangeByDateCamera ArrangeScheme = "date-camera"
+)
+
+// exifDateLayout is the format exiftool reports DateTimeOriginal in.
+const exifDateLayout = "2006:01:02 15:04:05"
+
+// ArrangeMove describes one file's planned (or completed) relocation.
+type ArrangeMove struct {
+	Src        string `json:"src"`
+	Dst        string `json:"dst"`
+	SymlinkDst string `json:"symlinkDst"`
+	Hash       string `json:"hash"`
+	Duplicate  bool   `json:"duplicate"`
+}
+
+// ArrangeFolder walks src and plans (or, unless dryRun, performs) moving
+// each file into dst/YYYY/MM/DD/<hash>.<ext> (content-addressed, keyed by
+// a SHA-1 of the file's bytes) alongside a human-browsable symlink under
+// dst/date/YYYY-MM-DD/. Capture date comes from EXIF when available,
+// falling back to the file's mtime. Files whose hash has already been
+// seen are treated as duplicates and are not moved again, only
+// symlinked. Progress is streamed as "arrange:progress" Wails events so
+// the frontend can render a progress bar.
+func (a *App) ArrangeFolder(src, dst string, scheme ArrangeScheme, dryRun bool) ([]ArrangeMove, error) {
+	var files []string
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		walkErr := fmt.Errorf("failed to walk %s: %w", src, err)
+		runtime.EventsEmit(a.ctx, "arrange:error", map[string]any{"error": walkErr.Error()})
+		return nil, walkErr
+	}
+
+	seen := make(map[string]string, len(files)) // content hash -> canonical dst path
+	moves := make([]ArrangeMove, 0, len(files))
+
+	for i, path := range files {
+		runtime.EventsEmit(a.ctx, "arrange:progress", map[string]any{
+			"current": i + 1,
+			"total":   len(files),
+			"path":    path,
+		})
+
+		hash, err := contentHash(path)
+		if err != nil {
+			runtime.LogWarningf(a.ctx, "arrange: skipping %s: %v", path, err)
+			continue
+		}
+
+		captureDate, camera := a.captureDateAndCamera(path)
+		ext := strings.ToLower(filepath.Ext(path))
+
+		canonicalDir := filepath.Join(dst,
+			fmt.Sprintf("%04d", captureDate.Year()),
+			fmt.Sprintf("%02d", captureDate.Month()),
+			fmt.Sprintf("%02d", captureDate.Day()))
+		if scheme == ArrangeByDateCamera && camera != "" {
+			canonicalDir = filepath.Join(canonicalDir, sanitizeComponent(camera))
+		}
+		canonicalDst := filepath.Join(canonicalDir, hash+ext)
+
+		// Suffix the symlink name with the content hash so two distinct
+		// files that share a basename (e.g. camera-reset numbering like
+		// IMG_0001.JPG from different cards imported the same day) don't
+		// clobber each other's entry in the date view.
+		symlinkDir := filepath.Join(dst, "date", captureDate.Format("2006-01-02"))
+		stem := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		shortHash := hash
+		if len(shortHash) > 8 {
+			shortHash = shortHash[:8]
+		}
+		symlinkDst := filepath.Join(symlinkDir, fmt.Sprintf("%s-%s%s", stem, shortHash, ext))
+
+		duplicate := false
+		if canonical, ok := seen[hash]; ok {
+			duplicate = true
+			canonicalDst = canonical
+		} else {
+			seen[hash] = canonicalDst
+		}
+
+		moves = append(moves, ArrangeMove{
+			Src:        path,
+			Dst:        canonicalDst,
+			SymlinkDst: symlinkDst,
+			Hash:       hash,
+			Duplicate:  duplicate,
+		})
+
+		if dryRun {
+			continue
+		}
+
+		if !duplicate {
+			if err := os.MkdirAll(canonicalDir, 0o755); err != nil {
+				return a.arrangeFailed(moves, fmt.Errorf("failed to create %s: %w", canonicalDir, err))
+			}
+			if err := moveFile(path, canonicalDst); err != nil {
+				return a.arrangeFailed(moves, fmt.Errorf("failed to move %s to %s: %w", path, canonicalDst, err))
+			}
+		}
+
+		if err := os.MkdirAll(symlinkDir, 0o755); err != nil {
+			return a.arrangeFailed(moves, fmt.Errorf("failed to create %s: %w", symlinkDir, err))
+		}
+		_ = os.Remove(symlinkDst) // drop a stale symlink, if any, before relinking
+		if err := os.Symlink(canonicalDst, symlinkDst); err != nil {
+			runtime.LogWarningf(a.ctx, "arrange: failed to symlink %s -> %s: %v", symlinkDst, canonicalDst, err)
+		}
+	}
+
+	runtime.EventsEmit(a.ctx, "arrange:done", map[string]any{"total": len(files)})
+	return moves, nil
+}
+
+// arrangeFailed emits a terminal "arrange:error" event before returning
+// err, so a mid-batch failure (permission denied, full disk, ...) always
+// leaves the frontend with a terminal signal instead of a progress bar
+// that never completes.
+func (a *App) arrangeFailed(moves []ArrangeMove, err error) ([]ArrangeMove, error) {
+	runtime.EventsEmit(a.ctx, "arrange:error", map[string]any{"error": err.Error()})
+	return moves, err
+}
+
+// captureDateAndCamera looks up the EXIF capture date and camera model
+// for path via the metadata loader, falling back to the file's mtime
+// when metadata is unavailable or unparsable.
+func (a *App) captureDateAndCamera(path string) (time.Time, string) {
+	if a.metadataLoader != nil {
+		if meta, err := a.metadataLoader.Get(path); err == nil {
+			camera := meta.Camera
+			if t, err := time.Parse(exifDateLayout, meta.CaptureTime); err == nil {
+				return t, camera
+			}
+			if info, statErr := os.Stat(path); statErr == nil {
+				return info.ModTime(), camera
+			}
+		}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Now(), ""
+	}
+	return info.ModTime(), ""
+}
+
+// contentHash returns the hex-encoded SHA-1 of path's contents.
+func contentHash(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	h := sha1.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// moveFile renames src to dst, falling back to copy-then-remove when
+// they're on different filesystems (os.Rename can't cross devices).
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
+
+// sanitizeComponent makes s safe to use as a single path component by
+// replacing path separators with underscores.
+func sanitizeComponent(s string) string {
+	s = strings.ReplaceAll(s, "/", "_")
+	s = strings.ReplaceAll(s, string(filepath.Separator), "_")
+	return strings.TrimSpace(s)
+}
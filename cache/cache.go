@@ -0,0 +1,145 @@
+// Package cache implements an on-disk store for encoded preview images,
+// keyed by a hash of each source file's path, mtime and size. It lets the app avoid re-decoding and
+// re-encoding full-resolution images (especially RAF and HDR files) every
+// time a thumbnail is requested.
+package cache
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultMaxBytes is the default LRU size cap for the on-disk cache.
+const DefaultMaxBytes int64 = 512 * 1024 * 1024 // 512MB
+
+// Cache stores encoded preview bytes on disk, keyed by a hash of the
+// source file's path, mtime and size. It evicts least-recently-used
+// entries once the total size exceeds maxBytes.
+type Cache struct {
+	dir      string
+	maxBytes int64
+	mu       sync.Mutex
+}
+
+// New creates a Cache rooted at dir, creating it if necessary. A maxBytes
+// of 0 selects DefaultMaxBytes.
+func New(dir string, maxBytes int64) (*Cache, error) {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache dir %s: %w", dir, err)
+	}
+	return &Cache{dir: dir, maxBytes: maxBytes}, nil
+}
+
+// Key derives the cache key for path at the given maxDim from the file's
+// path, size and modification time, so a changed or replaced file is
+// never served a stale preview.
+func (c *Cache) Key(path string, maxDim int) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	h := sha1.New()
+	fmt.Fprintf(h, "%s|%d|%d|%d", path, info.ModTime().UnixNano(), info.Size(), maxDim)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (c *Cache) entryPath(key string) string {
+	return filepath.Join(c.dir, key)
+}
+
+// Get returns the cached bytes for key, if present. It touches the
+// entry's modification time so the LRU eviction order reflects recency
+// of use rather than just creation time.
+func (c *Cache) Get(key string) ([]byte, bool) {
+	path := c.entryPath(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return data, true
+}
+
+// Put writes data under key and evicts older entries if the cache now
+// exceeds its size cap.
+func (c *Cache) Put(key string, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	path := c.entryPath(key)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write cache entry %s: %w", path, err)
+	}
+	return c.evict()
+}
+
+// Clear removes every entry from the cache.
+func (c *Cache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read cache dir %s: %w", c.dir, err)
+	}
+	for _, entry := range entries {
+		if err := os.Remove(filepath.Join(c.dir, entry.Name())); err != nil {
+			return fmt.Errorf("failed to remove cache entry %s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+// evict removes the least-recently-used entries until the cache is back
+// under its size cap. Callers must hold c.mu.
+func (c *Cache) evict() error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read cache dir %s: %w", c.dir, err)
+	}
+
+	type item struct {
+		path    string
+		size    int64
+		modTime int64
+	}
+	items := make([]item, 0, len(entries))
+	var total int64
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		items = append(items, item{
+			path:    filepath.Join(c.dir, entry.Name()),
+			size:    info.Size(),
+			modTime: info.ModTime().UnixNano(),
+		})
+		total += info.Size()
+	}
+	if total <= c.maxBytes {
+		return nil
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].modTime < items[j].modTime })
+	for _, it := range items {
+		if total <= c.maxBytes {
+			break
+		}
+		if err := os.Remove(it.path); err != nil {
+			continue
+		}
+		total -= it.size
+	}
+	return nil
+}
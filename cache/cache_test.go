@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestPutGetRoundTrip(t *testing.T) {
+	c, err := New(t.TempDir(), DefaultMaxBytes)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := c.Put("key", []byte("hello")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	got, ok := c.Get("key")
+	if !ok {
+		t.Fatal("Get: entry not found")
+	}
+	if string(got) != "hello" {
+		t.Errorf("Get: got %q, want %q", got, "hello")
+	}
+
+	if _, ok := c.Get("missing"); ok {
+		t.Error("Get: expected miss for unwritten key")
+	}
+}
+
+func TestEvictRemovesLeastRecentlyUsed(t *testing.T) {
+	c, err := New(t.TempDir(), 10) // small cap so a third 4-byte entry forces eviction
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := c.Put("oldest", []byte("aaaa")); err != nil {
+		t.Fatalf("Put(oldest): %v", err)
+	}
+	backdate(t, c.entryPath("oldest"), time.Unix(100, 0))
+
+	if err := c.Put("middle", []byte("bbbb")); err != nil {
+		t.Fatalf("Put(middle): %v", err)
+	}
+	backdate(t, c.entryPath("middle"), time.Unix(200, 0))
+
+	// Pushes total size to 12 bytes, over the 10-byte cap, and is itself
+	// newer than both prior entries, so eviction must take "oldest" first.
+	if err := c.Put("newest", []byte("cccc")); err != nil {
+		t.Fatalf("Put(newest): %v", err)
+	}
+
+	if _, ok := c.Get("oldest"); ok {
+		t.Error("expected least-recently-used entry to be evicted")
+	}
+	if _, ok := c.Get("middle"); !ok {
+		t.Error("expected middle entry to survive eviction")
+	}
+	if _, ok := c.Get("newest"); !ok {
+		t.Error("expected newest entry to survive eviction")
+	}
+}
+
+func TestClearRemovesAllEntries(t *testing.T) {
+	c, err := New(t.TempDir(), DefaultMaxBytes)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := c.Put("key", []byte("hello")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	if err := c.Clear(); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if _, ok := c.Get("key"); ok {
+		t.Error("expected Get to miss after Clear")
+	}
+}
+
+// backdate sets path's mtime explicitly so LRU ordering in tests doesn't
+// depend on how much wall-clock time elapses between Put calls.
+func backdate(t *testing.T, path string, at time.Time) {
+	t.Helper()
+	if err := os.Chtimes(path, at, at); err != nil {
+		t.Fatalf("Chtimes(%s): %v", path, err)
+	}
+}
@@ -0,0 +1,215 @@
+// Package metadata extracts EXIF/XMP metadata for images and RAF files.
+// Lookups are batched through a Loader so many near-simultaneous
+// requests (e.g. a folder's worth of thumbnails) collapse into a single
+// exiftool subprocess invocation instead of one process per file.
+package metadata
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/barasher/go-exiftool"
+)
+
+// flushInterval and maxBatch bound how long a request waits to be
+// batched with others before the loader sends the batch to exiftool anyway.
+const (
+	flushInterval = 100 * time.Millisecond
+	maxBatch      = 100
+)
+
+// Metadata holds the subset of EXIF/XMP fields the UI cares about. Raw
+// carries every field exiftool returned, for callers that need more.
+type Metadata struct {
+	Path        string
+	Camera      string
+	Lens        string
+	Shutter     string
+	ISO         string
+	GPS         string
+	Orientation int
+	CaptureTime string
+	Raw         map[string]any
+}
+
+type request struct {
+	path     string
+	resultCh chan<- result
+}
+
+type result struct {
+	meta Metadata
+	err  error
+}
+
+// Loader batches Get calls that land within flushInterval of
+// each other (or once maxBatch requests have queued) into a single
+// exiftool invocation, then fans the per-file results back out.
+type Loader struct {
+	et *exiftool.Exiftool
+
+	mu      sync.Mutex
+	pending []request
+	timer   *time.Timer
+}
+
+// NewLoader starts the exiftool subprocess backing this Loader. Callers
+// should Close it on shutdown.
+func NewLoader() (*Loader, error) {
+	et, err := exiftool.NewExiftool()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start exiftool: %w", err)
+	}
+	return &Loader{et: et}, nil
+}
+
+// Close stops the underlying exiftool subprocess.
+func (l *Loader) Close() error {
+	return l.et.Close()
+}
+
+// Get returns metadata for path, batching the underlying exiftool call
+// with any other Get calls made within flushInterval.
+func (l *Loader) Get(path string) (Metadata, error) {
+	ch := make(chan result, 1)
+
+	l.mu.Lock()
+	l.pending = append(l.pending, request{path: path, resultCh: ch})
+	flushNow := len(l.pending) >= maxBatch
+	if l.timer == nil && !flushNow {
+		l.timer = time.AfterFunc(flushInterval, l.flush)
+	}
+	l.mu.Unlock()
+
+	if flushNow {
+		l.flush()
+	}
+
+	res := <-ch
+	return res.meta, res.err
+}
+
+// GetBatch looks up metadata for every path concurrently, relying on the
+// same batching as Get, and returns results in the same order as paths.
+func (l *Loader) GetBatch(paths []string) ([]Metadata, error) {
+	results := make([]Metadata, len(paths))
+	errs := make([]error, len(paths))
+
+	var wg sync.WaitGroup
+	for i, path := range paths {
+		wg.Add(1)
+		go func(i int, path string) {
+			defer wg.Done()
+			results[i], errs[i] = l.Get(path)
+		}(i, path)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return results, fmt.Errorf("failed to read metadata for %s: %w", paths[i], err)
+		}
+	}
+	return results, nil
+}
+
+// flush drains the pending queue and resolves it with a single exiftool
+// call, whether it was triggered by the timer or by hitting maxBatch.
+func (l *Loader) flush() {
+	l.mu.Lock()
+	batch := l.pending
+	l.pending = nil
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+	l.mu.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+
+	paths := make([]string, len(batch))
+	for i, req := range batch {
+		paths[i] = req.path
+	}
+
+	fileMetas := l.et.ExtractMetadata(paths...)
+	for i, req := range batch {
+		if i >= len(fileMetas) {
+			req.resultCh <- result{err: fmt.Errorf("exiftool returned no metadata for %s", req.path)}
+			close(req.resultCh)
+			continue
+		}
+		fm := fileMetas[i]
+		if fm.Err != nil {
+			req.resultCh <- result{err: fm.Err}
+			close(req.resultCh)
+			continue
+		}
+		req.resultCh <- result{meta: fromFields(req.path, fm.Fields)}
+		close(req.resultCh)
+	}
+}
+
+// fromFields maps exiftool's loosely-typed field map onto Metadata.
+func fromFields(path string, fields map[string]any) Metadata {
+	m := Metadata{Path: path, Raw: fields}
+
+	if v, ok := fields["Model"].(string); ok {
+		m.Camera = v
+	}
+	if v, ok := fields["LensModel"].(string); ok {
+		m.Lens = v
+	}
+	if v, ok := fields["ShutterSpeed"]; ok {
+		m.Shutter = fmt.Sprintf("%v", v)
+	} else if v, ok := fields["ExposureTime"]; ok {
+		m.Shutter = fmt.Sprintf("%v", v)
+	}
+	if v, ok := fields["ISO"]; ok {
+		m.ISO = fmt.Sprintf("%v", v)
+	}
+	if lat, ok := fields["GPSLatitude"]; ok {
+		if lon, ok := fields["GPSLongitude"]; ok {
+			m.GPS = fmt.Sprintf("%v,%v", lat, lon)
+		}
+	}
+	if v, ok := fields["DateTimeOriginal"].(string); ok {
+		m.CaptureTime = v
+	}
+	m.Orientation = orientationOf(fields["Orientation"])
+
+	return m
+}
+
+// orientationOf normalizes exiftool's Orientation field, which may come
+// back as a number or as a human-readable string like "Rotate 90 CW",
+// into the EXIF 1-8 orientation code. 1 (no transform) is the default.
+func orientationOf(v any) int {
+	switch val := v.(type) {
+	case int:
+		return val
+	case float64:
+		return int(val)
+	case string:
+		switch val {
+		case "Rotate 90 CW":
+			return 6
+		case "Rotate 180":
+			return 3
+		case "Rotate 270 CW":
+			return 8
+		case "Mirror horizontal":
+			return 2
+		case "Mirror vertical":
+			return 4
+		case "Mirror horizontal and rotate 270 CW":
+			return 5
+		case "Mirror horizontal and rotate 90 CW":
+			return 7
+		}
+	}
+	return 1
+}
@@ -0,0 +1,31 @@
+package metadata
+
+import "testing"
+
+func TestOrientationOf(t *testing.T) {
+	cases := []struct {
+		name string
+		in   any
+		want int
+	}{
+		{"int", 6, 6},
+		{"float64", float64(3), 3},
+		{"rotate 90 cw", "Rotate 90 CW", 6},
+		{"rotate 180", "Rotate 180", 3},
+		{"rotate 270 cw", "Rotate 270 CW", 8},
+		{"mirror horizontal", "Mirror horizontal", 2},
+		{"mirror vertical", "Mirror vertical", 4},
+		{"mirror horizontal and rotate 270 cw", "Mirror horizontal and rotate 270 CW", 5},
+		{"mirror horizontal and rotate 90 cw", "Mirror horizontal and rotate 90 CW", 7},
+		{"unrecognized string", "Horizontal (normal)", 1},
+		{"nil", nil, 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := orientationOf(tc.in); got != tc.want {
+				t.Errorf("orientationOf(%v) = %d, want %d", tc.in, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !vips
+
+package main
+
+import "fmt"
+
+// vipsEnabled reports whether this build was compiled with the vips tag.
+// Without it, the libvips backend isn't linked in and ReadImageWithOpts
+// falls back to the pure-Go decode path.
+const vipsEnabled = false
+
+// readImageVips is a stub for builds without the vips tag; see vips.go
+// for the real implementation.
+func (a *App) readImageVips(filePath string, opts ReadImageOpts) (string, error) {
+	return "", fmt.Errorf("libvips backend not built in (build with -tags vips)")
+}
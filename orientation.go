@@ -0,0 +1,81 @@
+package main
+
+import "image"
+
+// applyOrientation returns img transformed according to the EXIF
+// orientation codes 1-8, so previews display upright regardless of how
+// the camera wrote the pixel data.
+func applyOrientation(img image.Image, orientation int) image.Image {
+	switch orientation {
+	case 2:
+		return flipHorizontal(img)
+	case 3:
+		return rotate180(img)
+	case 4:
+		return flipVertical(img)
+	case 5:
+		return rotate270(flipHorizontal(img))
+	case 6:
+		return rotate90(img)
+	case 7:
+		return rotate90(flipHorizontal(img))
+	case 8:
+		return rotate270(img)
+	default:
+		return img
+	}
+}
+
+func flipHorizontal(img image.Image) image.Image {
+	b := img.Bounds()
+	dst := image.NewNRGBA(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			srcX := b.Max.X - 1 - (x - b.Min.X)
+			dst.Set(x, y, img.At(srcX, y))
+		}
+	}
+	return dst
+}
+
+func flipVertical(img image.Image) image.Image {
+	b := img.Bounds()
+	dst := image.NewNRGBA(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		srcY := b.Max.Y - 1 - (y - b.Min.Y)
+		for x := b.Min.X; x < b.Max.X; x++ {
+			dst.Set(x, y, img.At(x, srcY))
+		}
+	}
+	return dst
+}
+
+func rotate180(img image.Image) image.Image {
+	return flipHorizontal(flipVertical(img))
+}
+
+// rotate90 rotates img 90 degrees clockwise.
+func rotate90(img image.Image) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dst := image.NewNRGBA(image.Rect(0, 0, h, w))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			dst.Set(h-1-y, x, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// rotate270 rotates img 90 degrees counter-clockwise.
+func rotate270(img image.Image) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dst := image.NewNRGBA(image.Rect(0, 0, h, w))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			dst.Set(y, w-1-x, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// testLabels assigns a distinct, easily distinguished color to each
+// corner of a 2x2 test image so every applyOrientation case (including
+// the diagonal transpose/transverse ones) can be checked by position.
+var testLabels = map[string]color.NRGBA{
+	"A": {R: 255, A: 255},         // top-left: red
+	"B": {G: 255, A: 255},         // top-right: green
+	"C": {B: 255, A: 255},         // bottom-left: blue
+	"D": {R: 255, G: 255, A: 255}, // bottom-right: yellow
+}
+
+func newOrientationTestImage() image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, testLabels["A"])
+	img.Set(1, 0, testLabels["B"])
+	img.Set(0, 1, testLabels["C"])
+	img.Set(1, 1, testLabels["D"])
+	return img
+}
+
+func labelAt(img image.Image, x, y int) string {
+	r, g, b, _ := img.At(x, y).RGBA()
+	for label, c := range testLabels {
+		cr, cg, cb, _ := c.RGBA()
+		if cr == r && cg == g && cb == b {
+			return label
+		}
+	}
+	return fmt.Sprintf("unknown(%d,%d,%d)", r, g, b)
+}
+
+func TestApplyOrientationIdentity(t *testing.T) {
+	img := newOrientationTestImage()
+	if out := applyOrientation(img, 1); out != img {
+		t.Error("orientation 1 should return the image unchanged")
+	}
+}
+
+func TestApplyOrientation(t *testing.T) {
+	type pos struct{ x, y int }
+
+	cases := []struct {
+		name        string
+		orientation int
+		want        map[pos]string
+	}{
+		{"2 mirror horizontal", 2, map[pos]string{{0, 0}: "B", {1, 0}: "A", {0, 1}: "D", {1, 1}: "C"}},
+		{"3 rotate 180", 3, map[pos]string{{0, 0}: "D", {1, 0}: "C", {0, 1}: "B", {1, 1}: "A"}},
+		{"4 mirror vertical", 4, map[pos]string{{0, 0}: "C", {1, 0}: "D", {0, 1}: "A", {1, 1}: "B"}},
+		{"5 transpose", 5, map[pos]string{{0, 0}: "A", {1, 0}: "C", {0, 1}: "B", {1, 1}: "D"}},
+		{"6 rotate 90 cw", 6, map[pos]string{{0, 0}: "C", {1, 0}: "A", {0, 1}: "D", {1, 1}: "B"}},
+		{"7 transverse", 7, map[pos]string{{0, 0}: "D", {1, 0}: "B", {0, 1}: "C", {1, 1}: "A"}},
+		{"8 rotate 270 cw", 8, map[pos]string{{0, 0}: "B", {1, 0}: "D", {0, 1}: "A", {1, 1}: "C"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := applyOrientation(newOrientationTestImage(), tc.orientation)
+			b := out.Bounds()
+			if b.Dx() != 2 || b.Dy() != 2 {
+				t.Fatalf("orientation %d: got bounds %v, want 2x2", tc.orientation, b)
+			}
+			for p, wantLabel := range tc.want {
+				if got := labelAt(out, p.x, p.y); got != wantLabel {
+					t.Errorf("orientation %d: at (%d,%d) got %s, want %s", tc.orientation, p.x, p.y, got, wantLabel)
+				}
+			}
+		})
+	}
+}
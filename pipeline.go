@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// decodeWorkers bounds how many images are decoded/encoded concurrently
+// during a preload pass.
+const decodeWorkers = 4
+
+// preloadResult is what the decode/encode stage hands back for a single
+// path in the pipeline.
+type preloadResult struct {
+	Path    string
+	DataURL string
+	Err     error
+}
+
+// PreloadImages decodes up to ahead images from the front of paths on a
+// pool of workers and streams each result back to the frontend as it
+// completes, via "image:ready" / "image:error" Wails events, instead of
+// blocking the caller on a synchronous ReadImage round trip per file.
+// This lets the UI request N-ahead prefetch while the user keeps browsing.
+//
+// Each call cancels any preload pass still in flight from a previous call
+// before starting its own, so fast scrolling doesn't stack up unbounded
+// overlapping decode pools for paths the user has already left behind.
+func (a *App) PreloadImages(paths []string, ahead int) {
+	if len(paths) == 0 {
+		return
+	}
+	if ahead <= 0 || ahead > len(paths) {
+		ahead = len(paths)
+	}
+	targets := paths[:ahead]
+
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.preloadMu.Lock()
+	if a.preloadCancel != nil {
+		a.preloadCancel()
+	}
+	a.preloadCancel = cancel
+	a.preloadMu.Unlock()
+
+	pathCh := sourcePaths(ctx, targets)
+	resultCh := make(chan preloadResult)
+
+	workers := decodeWorkers
+	if workers > len(targets) {
+		workers = len(targets)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range pathCh {
+				select {
+				case resultCh <- decodeAndEncode(a, path):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(resultCh)
+	}()
+
+	go a.emitPreloadResults(ctx, resultCh)
+}
+
+// sourcePaths emits paths on a channel for the decode worker pool to
+// consume, mirroring the walk-and-emit shape of a directory source stage
+// but over an already-known list of files. It stops early once ctx is
+// cancelled, e.g. by a newer PreloadImages call superseding this one.
+func sourcePaths(ctx context.Context, paths []string) <-chan string {
+	ch := make(chan string)
+	go func() {
+		defer close(ch)
+		for _, path := range paths {
+			select {
+			case ch <- path:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// decodeAndEncode is the pipeline's decode/encode stage: it reuses
+// ReadImage (and therefore the thumbnail cache) to turn a path into a
+// base64 data URL, or an error to report back to the frontend.
+func decodeAndEncode(a *App, path string) preloadResult {
+	dataURL, err := a.ReadImage(path)
+	return preloadResult{Path: path, DataURL: dataURL, Err: err}
+}
+
+// emitPreloadResults is the pipeline's encoder/emit stage: it drains
+// resultCh and forwards each outcome to the frontend as a Wails event,
+// stopping early if ctx is cancelled so a superseded pass doesn't emit
+// events for paths the caller no longer cares about.
+func (a *App) emitPreloadResults(ctx context.Context, resultCh <-chan preloadResult) {
+	for {
+		select {
+		case res, ok := <-resultCh:
+			if !ok {
+				return
+			}
+			if res.Err != nil {
+				runtime.EventsEmit(a.ctx, "image:error", map[string]string{
+					"path":  res.Path,
+					"error": res.Err.Error(),
+				})
+				continue
+			}
+			runtime.EventsEmit(a.ctx, "image:ready", map[string]string{
+				"path": res.Path,
+				"data": res.DataURL,
+			})
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package raw
+
+// Format identifies an image container detected from its leading bytes,
+// independent of whatever extension the file happens to have.
+type Format string
+
+const (
+	FormatUnknown Format = ""
+	FormatJPEG    Format = "jpeg"
+	FormatPNG     Format = "png"
+	FormatGIF     Format = "gif"
+	FormatWebP    Format = "webp"
+	FormatBMP     Format = "bmp"
+	FormatHEIC    Format = "heic"
+	FormatRAF     Format = "raf"
+)
+
+// rafMagic is the file signature Fujifilm RAF files start with.
+const rafMagic = "FUJIFILMCCD-RAW"
+
+// SniffFormat identifies the image format of header, the first 14-16
+// bytes of a file, by magic number rather than trusting its extension.
+// It returns FormatUnknown if none of the recognized signatures match.
+func SniffFormat(header []byte) Format {
+	switch {
+	case len(header) >= 3 && header[0] == 0xFF && header[1] == 0xD8 && header[2] == 0xFF:
+		return FormatJPEG
+	case len(header) >= 4 && header[0] == 0x89 && header[1] == 0x50 && header[2] == 0x4E && header[3] == 0x47:
+		return FormatPNG
+	case len(header) >= 6 && (string(header[:6]) == "GIF87a" || string(header[:6]) == "GIF89a"):
+		return FormatGIF
+	case len(header) >= 12 && string(header[0:4]) == "RIFF" && string(header[8:12]) == "WEBP":
+		return FormatWebP
+	case len(header) >= 2 && header[0] == 'B' && header[1] == 'M':
+		return FormatBMP
+	case len(header) >= 12 && string(header[4:8]) == "ftyp" && isHEICBrand(string(header[8:12])):
+		return FormatHEIC
+	case len(header) >= len(rafMagic) && string(header[:len(rafMagic)]) == rafMagic:
+		return FormatRAF
+	default:
+		return FormatUnknown
+	}
+}
+
+// isHEICBrand reports whether an ISO base media file's major brand
+// indicates HEIC/HEIF content.
+func isHEICBrand(brand string) bool {
+	switch brand {
+	case "heic", "heix", "mif1", "msf1":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,32 @@
+package raw
+
+import "testing"
+
+func TestSniffFormat(t *testing.T) {
+	cases := []struct {
+		name   string
+		header []byte
+		want   Format
+	}{
+		{"jpeg", []byte{0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x10, 0x4A, 0x46}, FormatJPEG},
+		{"png", []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}, FormatPNG},
+		{"gif87a", []byte("GIF87a")[:6], FormatGIF},
+		{"gif89a", []byte("GIF89a")[:6], FormatGIF},
+		{"webp", append([]byte("RIFF\x00\x00\x00\x00"), []byte("WEBP")...), FormatWebP},
+		{"bmp", []byte("BM\x00\x00\x00\x00"), FormatBMP},
+		{"heic", append([]byte("\x00\x00\x00\x18ftyp"), []byte("heic")...), FormatHEIC},
+		{"mif1", append([]byte("\x00\x00\x00\x18ftyp"), []byte("mif1")...), FormatHEIC},
+		{"raf", []byte("FUJIFILMCCD-RAW"), FormatRAF},
+		{"unknown", []byte("not an image header"), FormatUnknown},
+		{"too short", []byte{0xFF}, FormatUnknown},
+		{"empty", nil, FormatUnknown},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := SniffFormat(tc.header); got != tc.want {
+				t.Errorf("SniffFormat(%q) = %q, want %q", tc.header, got, tc.want)
+			}
+		})
+	}
+}